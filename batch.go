@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ===================== Batch VIN decoding =====================
+
+const maxBatchVINs = 100
+
+// batchPersistTimeout bounds the final upsert transaction. It deliberately
+// runs on its own context rather than the request's: a client that
+// disconnects mid-batch shouldn't cause us to discard VINs we already paid
+// to decode.
+const batchPersistTimeout = 10 * time.Second
+
+// defaultBatchWorkers is how many VINs we decode concurrently when the
+// BATCH_WORKERS env var isn't set.
+const defaultBatchWorkers = 8
+
+type batchRequest struct {
+	VINs []string `json:"vins"`
+}
+
+type batchResult struct {
+	VIN    string                 `json:"vin"`
+	Status string                 `json:"status"` // "cached", "decoded" or "error"
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// batchHandler decodes a list of VINs against the "nhtsa" provider using a
+// bounded worker pool, reusing the DB cache per VIN and upserting every
+// successfully decoded result in a single transaction at the end.
+func batchHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonWrite(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+			return
+		}
+		if len(req.VINs) == 0 {
+			jsonWrite(w, http.StatusBadRequest, map[string]string{"error": "vins must not be empty"})
+			return
+		}
+		if len(req.VINs) > maxBatchVINs {
+			jsonWrite(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("at most %d vins per batch", maxBatchVINs)})
+			return
+		}
+
+		provider, err := GetProvider("nhtsa")
+		if err != nil {
+			jsonWrite(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		results, metrics := decodeBatch(r.Context(), db, provider, req.VINs)
+		log.Printf("batch decode: vins=%d cache_hits=%d upstream_calls=%d failures=%d",
+			len(req.VINs), metrics.cacheHits, metrics.upstreamCalls, metrics.failures)
+
+		jsonWrite(w, http.StatusOK, results)
+	}
+}
+
+type batchMetrics struct {
+	cacheHits     int
+	upstreamCalls int
+	failures      int
+}
+
+// decodeBatch fans the VINs out across a bounded worker pool, honoring ctx
+// cancellation, then upserts every decoded vehicle in a single transaction.
+func decodeBatch(ctx context.Context, db *sql.DB, provider VehicleProvider, vins []string) ([]batchResult, batchMetrics) {
+	workers := batchWorkerCount()
+	if workers > len(vins) {
+		workers = len(vins)
+	}
+
+	results := make([]batchResult, len(vins))
+	decoded := make([]*DecodedVehicle, len(vins))
+
+	var mu sync.Mutex
+	metrics := batchMetrics{}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				vin := vins[idx]
+
+				if v, err := getVehicleByVIN(ctx, db, vin); err == nil {
+					results[idx] = batchResult{VIN: vin, Status: "cached", Data: v}
+					mu.Lock()
+					metrics.cacheHits++
+					mu.Unlock()
+					continue
+				}
+
+				mu.Lock()
+				metrics.upstreamCalls++
+				mu.Unlock()
+
+				dv, err := provider.Decode(ctx, vin)
+				if err != nil {
+					results[idx] = batchResult{VIN: vin, Status: "error", Error: err.Error()}
+					mu.Lock()
+					metrics.failures++
+					mu.Unlock()
+					continue
+				}
+
+				decoded[idx] = dv
+				results[idx] = batchResult{VIN: vin, Status: "decoded"}
+			}
+		}()
+	}
+
+feed:
+	for i := range vins {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	persistCtx, cancel := context.WithTimeout(context.Background(), batchPersistTimeout)
+	defer cancel()
+
+	if err := upsertVehicleBatch(persistCtx, db, decoded); err != nil {
+		log.Println("erro salvando batch no banco:", err)
+
+		// The batch didn't land: don't report these VINs as "decoded" with
+		// no data, since the client would have no signal the write failed.
+		for i, dv := range decoded {
+			if dv == nil || results[i].Status != "decoded" {
+				continue
+			}
+			results[i] = batchResult{VIN: dv.VIN, Status: "error", Error: fmt.Sprintf("decoded but failed to persist: %v", err)}
+			metrics.failures++
+		}
+		return results, metrics
+	}
+
+	// Fill in data for freshly decoded VINs now that they're persisted.
+	for i, dv := range decoded {
+		if dv == nil || results[i].Status != "decoded" {
+			continue
+		}
+		if v, err := getVehicleByVIN(persistCtx, db, dv.VIN); err == nil {
+			results[i].Data = v
+		}
+	}
+
+	return results, metrics
+}
+
+// upsertVehicleBatch writes every non-nil decoded vehicle inside a single
+// transaction so a batch either fully lands or fully rolls back.
+func upsertVehicleBatch(ctx context.Context, db *sql.DB, decoded []*DecodedVehicle) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, dv := range decoded {
+		if dv == nil {
+			continue
+		}
+		if err := upsertVehicleTx(ctx, tx, dv); err != nil {
+			return fmt.Errorf("upsert %s: %w", dv.VIN, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func batchWorkerCount() int {
+	if n, err := strconv.Atoi(os.Getenv("BATCH_WORKERS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultBatchWorkers
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// failingTxConn always misses the cache and always fails to begin a
+// transaction, simulating a DB that's reachable for reads but rejects
+// writes (e.g. in read-only failover).
+type failingTxConn struct{}
+
+func (failingTxConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (failingTxConn) Close() error                        { return nil }
+func (failingTxConn) Begin() (driver.Tx, error)           { return nil, errors.New("begin not supported") }
+
+func (failingTxConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return nil, errors.New("cache miss")
+}
+
+type failingTxConnector struct{}
+
+func (failingTxConnector) Connect(context.Context) (driver.Conn, error) { return failingTxConn{}, nil }
+func (failingTxConnector) Driver() driver.Driver                        { return failingTxDriver{} }
+
+type failingTxDriver struct{}
+
+func (failingTxDriver) Open(string) (driver.Conn, error) { return failingTxConn{}, nil }
+
+// fakeDecodeProvider decodes any VIN instantly without touching the network.
+type fakeDecodeProvider struct{}
+
+func (fakeDecodeProvider) Name() string { return "fake" }
+func (fakeDecodeProvider) Decode(_ context.Context, vin string) (*DecodedVehicle, error) {
+	return &DecodedVehicle{VIN: vin, Make: "TOYOTA", Source: "fake", Raw: map[string]interface{}{}}, nil
+}
+
+// TestDecodeBatchReportsErrorsWhenPersistFails ensures a failed batch
+// transaction surfaces as per-VIN errors instead of silently reporting
+// "decoded" results that were never actually saved.
+func TestDecodeBatchReportsErrorsWhenPersistFails(t *testing.T) {
+	db := sql.OpenDB(failingTxConnector{})
+	vins := []string{"VIN1", "VIN2", "VIN3"}
+
+	results, metrics := decodeBatch(context.Background(), db, fakeDecodeProvider{}, vins)
+
+	if metrics.failures != len(vins) {
+		t.Fatalf("expected %d failures, got %d", len(vins), metrics.failures)
+	}
+	for _, r := range results {
+		if r.Status != "error" {
+			t.Fatalf("expected status error for %s, got %q (data=%v)", r.VIN, r.Status, r.Data)
+		}
+		if !strings.Contains(r.Error, "failed to persist") {
+			t.Fatalf("expected persist failure message for %s, got %q", r.VIN, r.Error)
+		}
+		if r.Data != nil {
+			t.Fatalf("expected no data for a VIN that failed to persist, got %v", r.Data)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// ===================== Banco de dados =====================
+
+// execer is the subset of *sql.DB / *sql.Tx that upsertVehicleWith needs,
+// so the same insert can run standalone or as part of a larger transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func upsertVehicleWith(ctx context.Context, ex execer, v *DecodedVehicle) error {
+	rawJSON, _ := json.Marshal(v.Raw)
+	_, err := ex.ExecContext(ctx, `
+        INSERT INTO vehicles (
+            vin, make, model, model_year, manufacturer,
+            plant_country, plant_state, body_class, engine_cylinders, fuel_type, source, raw
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+        ON CONFLICT (vin) DO UPDATE SET
+            make=$2, model=$3, model_year=$4, manufacturer=$5,
+            plant_country=$6, plant_state=$7, body_class=$8,
+            engine_cylinders=$9, fuel_type=$10, source=$11, raw=$12,
+            last_updated=now();
+    `,
+		v.VIN, v.Make, v.Model, v.ModelYear, v.Manufacturer,
+		v.PlantCountry, v.PlantState, v.BodyClass, v.EngineCylinders, v.FuelType, v.Source, rawJSON,
+	)
+	return err
+}
+
+func upsertVehicle(ctx context.Context, db *sql.DB, v *DecodedVehicle) error {
+	return upsertVehicleWith(ctx, db, v)
+}
+
+// upsertVehicleTx upserts within an already-open transaction, used by the
+// batch decode endpoint to land a whole batch atomically.
+func upsertVehicleTx(ctx context.Context, tx *sql.Tx, v *DecodedVehicle) error {
+	return upsertVehicleWith(ctx, tx, v)
+}
+
+func getVehicleByVIN(ctx context.Context, db *sql.DB, vin string) (map[string]interface{}, error) {
+	row := db.QueryRowContext(ctx, `SELECT vin, make, model, model_year, manufacturer,
+                               plant_country, plant_state, body_class, engine_cylinders, fuel_type, source, raw
+                        FROM vehicles WHERE vin=$1`, vin)
+
+	var (
+		vinVal, make, model, modelYear, manufacturer, plantCountry, plantState, bodyClass, engineCylinders, fuelType, source string
+		raw                                                                                                                  []byte
+	)
+	err := row.Scan(&vinVal, &make, &model, &modelYear, &manufacturer, &plantCountry, &plantState, &bodyClass, &engineCylinders, &fuelType, &source, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawJSON map[string]interface{}
+	_ = json.Unmarshal(raw, &rawJSON)
+
+	return map[string]interface{}{
+		"vin":              vinVal,
+		"make":             make,
+		"model":            model,
+		"model_year":       modelYear,
+		"manufacturer":     manufacturer,
+		"plant_country":    plantCountry,
+		"plant_state":      plantState,
+		"body_class":       bodyClass,
+		"engine_cylinders": engineCylinders,
+		"fuel_type":        fuelType,
+		"source":           source,
+		"raw":              rawJSON,
+	}, nil
+}
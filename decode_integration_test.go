@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ---- a no-op sql driver: every query misses the cache immediately, so
+// tests never touch a real database. ----
+
+type noopDriver struct{}
+
+func (noopDriver) Open(string) (driver.Conn, error) { return noopConn{}, nil }
+
+type noopConn struct{}
+
+func (noopConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (noopConn) Close() error                        { return nil }
+func (noopConn) Begin() (driver.Tx, error)           { return nil, errors.New("begin not supported") }
+
+func (noopConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return nil, errors.New("cache miss")
+}
+
+func init() {
+	sql.Register("noop", noopDriver{})
+}
+
+func newNoopDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("noop", "")
+	if err != nil {
+		t.Fatalf("open noop db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// blockingProvider simulates a slow upstream: Decode hangs until ctx is
+// done, then returns ctx.Err(), same as a real HTTP client would once its
+// request context is canceled.
+type blockingProvider struct{ name string }
+
+func (p *blockingProvider) Name() string { return p.name }
+
+func (p *blockingProvider) Decode(ctx context.Context, vin string) (*DecodedVehicle, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func ensureBlockingProviderRegistered(name string) {
+	if _, err := GetProvider(name); err != nil {
+		RegisterProvider(&blockingProvider{name: name})
+	}
+}
+
+// TestDecodeHandlerCancelReturnsPromptly exercises a client disconnect mid
+// decode: the upstream provider is hung, the request context is canceled,
+// and the handler must return 499 immediately instead of hanging, leaving
+// no goroutines behind.
+func TestDecodeHandlerCancelReturnsPromptly(t *testing.T) {
+	ensureBlockingProviderRegistered("slowtest-cancel")
+	db := newNoopDB(t)
+
+	router := chi.NewRouter()
+	router.Get("/decode/{provider}/{vin}", decodeHandler(db))
+
+	baseline := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/decode/slowtest-cancel/VIN123", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond) // let the request reach the blocked Decode call
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not return promptly after the client context was canceled")
+	}
+
+	if rec.Code != statusClientClosedRequest {
+		t.Fatalf("expected status %d, got %d", statusClientClosedRequest, rec.Code)
+	}
+
+	assertNoGoroutineLeak(t, baseline)
+}
+
+// TestDecodeHandlerDeadlineReturns504 covers the upstream-timeout path: the
+// request's own deadline expires before the slow provider ever answers.
+func TestDecodeHandlerDeadlineReturns504(t *testing.T) {
+	ensureBlockingProviderRegistered("slowtest-deadline")
+	db := newNoopDB(t)
+
+	router := chi.NewRouter()
+	router.Get("/decode/{provider}/{vin}", decodeHandler(db))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/decode/slowtest-deadline/VIN123", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d, got %d", http.StatusGatewayTimeout, rec.Code)
+	}
+}
+
+func assertNoGoroutineLeak(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= baseline {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("goroutine count did not return to baseline %d, still at %d", baseline, runtime.NumGoroutine())
+}
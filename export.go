@@ -0,0 +1,190 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ===================== GET /vehicles/export =====================
+
+// exportColumns lists every column streamed by the export endpoint, in a
+// stable order shared by both the NDJSON and CSV encodings.
+var exportColumns = []string{
+	"vin", "make", "model", "model_year", "manufacturer",
+	"plant_country", "plant_state", "body_class", "engine_cylinders", "fuel_type", "source",
+}
+
+// exportVehiclesHandler streams every row in vehicles as the requested
+// format without buffering the full result set in memory: rows are read
+// one at a time from the DB cursor and written straight to the response,
+// with periodic flushes so the client sees data as it arrives.
+func exportVehiclesHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			jsonWrite(w, http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
+			return
+		}
+
+		q := r.URL.Query()
+		where, args := exportFilterClause(q)
+
+		limit := 0
+		if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 {
+			limit = n
+		}
+
+		query := `SELECT ` + joinColumns(exportColumns) + `, raw FROM vehicles` + where + ` ORDER BY vin`
+		if limit > 0 {
+			args = append(args, limit)
+			query += ` LIMIT $` + strconv.Itoa(len(args))
+		}
+
+		rows, err := db.QueryContext(r.Context(), query, args...)
+		if err != nil {
+			jsonWrite(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer rows.Close()
+
+		format := q.Get("format")
+		if format == "csv" {
+			streamCSV(w, flusher, r, rows)
+			return
+		}
+		streamNDJSON(w, flusher, r, rows)
+	}
+}
+
+func exportFilterClause(q map[string][]string) (string, []interface{}) {
+	clauses := []string{}
+	args := []interface{}{}
+
+	if v := first(q, "make"); v != "" {
+		args = append(args, v)
+		clauses = append(clauses, "make=$"+strconv.Itoa(len(args)))
+	}
+	if v := first(q, "updated_since"); v != "" {
+		args = append(args, v)
+		clauses = append(clauses, "last_updated >= $"+strconv.Itoa(len(args)))
+	}
+
+	if len(clauses) == 0 {
+		return "", nil
+	}
+
+	where := " WHERE "
+	for i, c := range clauses {
+		if i > 0 {
+			where += " AND "
+		}
+		where += c
+	}
+	return where, args
+}
+
+func first(q map[string][]string, key string) string {
+	if vals, ok := q[key]; ok && len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+func joinColumns(cols []string) string {
+	out := ""
+	for i, c := range cols {
+		if i > 0 {
+			out += ", "
+		}
+		out += c
+	}
+	return out
+}
+
+// streamNDJSON writes one JSON object per row, flushing after each write so
+// a slow or disconnecting client can't make us buffer the whole table.
+func streamNDJSON(w http.ResponseWriter, flusher http.Flusher, r *http.Request, rows *sql.Rows) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+
+	for rows.Next() {
+		if r.Context().Err() != nil {
+			return
+		}
+
+		row, raw, err := scanExportRow(rows)
+		if err != nil {
+			return
+		}
+		row["raw"] = raw
+
+		if err := enc.Encode(row); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// streamCSV writes a header row followed by one CSV row per vehicle. The
+// raw JSON blob is omitted since it doesn't flatten sensibly into CSV.
+func streamCSV(w http.ResponseWriter, flusher http.Flusher, r *http.Request, rows *sql.Rows) {
+	w.Header().Set("Content-Type", "text/csv")
+	cw := csv.NewWriter(w)
+	_ = cw.Write(exportColumns)
+	cw.Flush()
+	flusher.Flush()
+
+	for rows.Next() {
+		if r.Context().Err() != nil {
+			return
+		}
+
+		row, _, err := scanExportRow(rows)
+		if err != nil {
+			return
+		}
+
+		record := make([]string, len(exportColumns))
+		for i, col := range exportColumns {
+			record[i] = fmt.Sprintf("%v", row[col])
+		}
+
+		if err := cw.Write(record); err != nil {
+			return
+		}
+		cw.Flush()
+		flusher.Flush()
+	}
+}
+
+func scanExportRow(rows *sql.Rows) (map[string]interface{}, map[string]interface{}, error) {
+	var (
+		vin, make, model, modelYear, manufacturer, plantCountry, plantState, bodyClass, engineCylinders, fuelType, source string
+		raw                                                                                                               []byte
+	)
+	if err := rows.Scan(&vin, &make, &model, &modelYear, &manufacturer, &plantCountry, &plantState, &bodyClass, &engineCylinders, &fuelType, &source, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	var rawJSON map[string]interface{}
+	_ = json.Unmarshal(raw, &rawJSON)
+
+	row := map[string]interface{}{
+		"vin":              vin,
+		"make":             make,
+		"model":            model,
+		"model_year":       modelYear,
+		"manufacturer":     manufacturer,
+		"plant_country":    plantCountry,
+		"plant_state":      plantState,
+		"body_class":       bodyClass,
+		"engine_cylinders": engineCylinders,
+		"fuel_type":        fuelType,
+		"source":           source,
+	}
+	return row, rawJSON, nil
+}
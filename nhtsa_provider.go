@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ===================== NHTSA (vPIC) provider =====================
+
+type NHTSAResponse struct {
+	Count          int                      `json:"Count"`
+	Message        string                   `json:"Message"`
+	SearchCriteria string                   `json:"SearchCriteria"`
+	Results        []map[string]interface{} `json:"Results"`
+}
+
+// nhtsaBaseURL is the public vPIC API; overridable so tests can point it at
+// an httptest server instead.
+const nhtsaBaseURL = "https://vpic.nhtsa.dot.gov/api/vehicles"
+
+// NHTSAProvider decodes VINs against the public NHTSA vPIC API. It is rate
+// limited client-side since vPIC has no documented quota but is known to
+// throttle bursty callers.
+type NHTSAProvider struct {
+	baseURL string
+	client  *http.Client
+	limiter *rate.Limiter
+}
+
+func NewNHTSAProvider() *NHTSAProvider {
+	return newNHTSAProvider(nhtsaBaseURL, &http.Client{Timeout: 12 * time.Second}, rate.NewLimiter(rate.Limit(5), 5))
+}
+
+func newNHTSAProvider(baseURL string, client *http.Client, limiter *rate.Limiter) *NHTSAProvider {
+	return &NHTSAProvider{baseURL: baseURL, client: client, limiter: limiter}
+}
+
+func (p *NHTSAProvider) Name() string { return "nhtsa" }
+
+func (p *NHTSAProvider) Decode(ctx context.Context, vin string) (*DecodedVehicle, error) {
+	if vin == "" {
+		return nil, fmt.Errorf("vin vazio")
+	}
+	if err := p.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("nhtsa rate limiter: %w", err)
+	}
+
+	nr, err := p.fetch(ctx, vin)
+	if err != nil {
+		return nil, err
+	}
+
+	var flat map[string]interface{}
+	if len(nr.Results) > 0 {
+		flat = nr.Results[0]
+	} else {
+		flat = map[string]interface{}{}
+	}
+
+	return &DecodedVehicle{
+		VIN:             vin,
+		Make:            str(flat["Make"]),
+		Model:           str(flat["Model"]),
+		ModelYear:       str(flat["ModelYear"]),
+		Manufacturer:    str(flat["Manufacturer"]),
+		PlantCountry:    str(flat["PlantCountry"]),
+		PlantState:      str(flat["PlantState"]),
+		BodyClass:       str(flat["BodyClass"]),
+		EngineCylinders: str(flat["EngineCylinders"]),
+		FuelType:        str(flat["FuelTypePrimary"]),
+		Source:          p.Name(),
+		Raw:             flat,
+	}, nil
+}
+
+func (p *NHTSAProvider) fetch(ctx context.Context, vin string) (*NHTSAResponse, error) {
+	url := fmt.Sprintf("%s/DecodeVinValues/%s?format=json", p.baseURL, vin)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nhtsa status %d; body: %s", resp.StatusCode, string(body))
+	}
+
+	var nr NHTSAResponse
+	if err := json.Unmarshal(body, &nr); err != nil {
+		return nil, fmt.Errorf("unmarshal nhtsa json: %w", err)
+	}
+	return &nr, nil
+}
+
+// str coerces the loosely-typed vPIC/JSON fields (often interface{} wrapping
+// a string, but occasionally nil) into a plain string.
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func newTestNHTSAProvider(handler http.HandlerFunc) (*NHTSAProvider, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+	p := newNHTSAProvider(srv.URL, &http.Client{Timeout: 2 * time.Second}, rate.NewLimiter(rate.Inf, 1))
+	return p, srv
+}
+
+func TestNHTSAProviderDecodeSuccess(t *testing.T) {
+	p, srv := newTestNHTSAProvider(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"Count": 1,
+			"Results": [{"Make": "TOYOTA", "Model": "Corolla", "ModelYear": "2020", "FuelTypePrimary": "Gasoline"}]
+		}`))
+	})
+	defer srv.Close()
+
+	dv, err := p.Decode(context.Background(), "1HGCM82633A123456")
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if dv.Make != "TOYOTA" || dv.Model != "Corolla" || dv.FuelType != "Gasoline" {
+		t.Fatalf("unexpected decoded vehicle: %+v", dv)
+	}
+	if dv.Source != "nhtsa" {
+		t.Fatalf("expected source nhtsa, got %q", dv.Source)
+	}
+}
+
+func TestNHTSAProviderDecodeNon200(t *testing.T) {
+	p, srv := newTestNHTSAProvider(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	})
+	defer srv.Close()
+
+	if _, err := p.Decode(context.Background(), "1HGCM82633A123456"); err == nil {
+		t.Fatal("expected error for non-200 upstream response")
+	}
+}
+
+func TestNHTSAProviderDecodeEmptyResults(t *testing.T) {
+	p, srv := newTestNHTSAProvider(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Count": 0, "Results": []}`))
+	})
+	defer srv.Close()
+
+	dv, err := p.Decode(context.Background(), "1HGCM82633A123456")
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if dv.Make != "" || dv.Model != "" {
+		t.Fatalf("expected empty fields for empty Results, got %+v", dv)
+	}
+}
+
+func TestNHTSAProviderDecodeRateLimiterCtxCancel(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Count": 0, "Results": []}`))
+	}))
+	defer srv.Close()
+
+	// burst=1 with an effectively-infinite refill interval: the first call
+	// consumes the only token, the second must block on limiter.Wait and
+	// should return once ctx is canceled rather than hang forever.
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	p := newNHTSAProvider(srv.URL, &http.Client{Timeout: 2 * time.Second}, limiter)
+
+	if _, err := p.Decode(context.Background(), "1HGCM82633A123456"); err != nil {
+		t.Fatalf("first Decode should consume the burst token without error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := p.Decode(ctx, "1HGCM82633A123456"); err == nil {
+		t.Fatal("expected error from canceled context while waiting on the rate limiter")
+	}
+	if calls != 1 {
+		t.Fatalf("expected upstream to be called exactly once, got %d", calls)
+	}
+}
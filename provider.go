@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ===================== Vehicle provider registry =====================
+
+// DecodedVehicle is the normalized shape every VehicleProvider must produce,
+// regardless of how the upstream source represents the data.
+type DecodedVehicle struct {
+	VIN             string
+	Make            string
+	Model           string
+	ModelYear       string
+	Manufacturer    string
+	PlantCountry    string
+	PlantState      string
+	BodyClass       string
+	EngineCylinders string
+	FuelType        string
+	Source          string
+	Raw             map[string]interface{}
+}
+
+// VehicleProvider decodes a VIN against a specific upstream data source
+// (NHTSA vPIC, a manufacturer API, etc). Implementations are registered by
+// name and selected per request via RegisterProvider/GetProvider.
+type VehicleProvider interface {
+	// Name is the registry key, e.g. "nhtsa" or "tesla".
+	Name() string
+	Decode(ctx context.Context, vin string) (*DecodedVehicle, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]VehicleProvider{}
+)
+
+// RegisterProvider makes a VehicleProvider available under the given name.
+// It is expected to be called from main() during startup; it panics on a
+// duplicate name since that indicates a wiring bug, not a runtime error.
+func RegisterProvider(p VehicleProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	name := p.Name()
+	if _, exists := providers[name]; exists {
+		panic(fmt.Sprintf("provider %q already registered", name))
+	}
+	providers[name] = p
+}
+
+// GetProvider looks up a registered VehicleProvider by name.
+func GetProvider(name string) (VehicleProvider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+	return p, nil
+}
+
+// ProviderNames returns the names of all registered providers, useful for
+// error messages and the default-provider fallback.
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	return names
+}
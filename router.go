@@ -0,0 +1,32 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ===================== Routing =====================
+
+// newRouter wires every HTTP route onto a chi router, replacing the old
+// manual r.URL.Path prefix-slicing with named path params.
+func newRouter(db *sql.DB) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/healthz", healthHandler)
+
+	r.Get("/decode/{vin}", decodeDefaultHandler(db))
+	r.Get("/decode/{provider}/{vin}", decodeHandler(db))
+
+	// /nhtsa/{vin} predates the {provider} generalization; kept as an
+	// alias so existing clients aren't silently 404'd.
+	r.Get("/nhtsa/{vin}", decodeNHTSAHandler(db))
+	r.Post("/nhtsa/batch", batchHandler(db))
+
+	r.Get("/vehicles", listVehiclesHandler(db))
+	r.Get("/vehicles/export", exportVehiclesHandler(db))
+	r.Get("/vehicles/{vin}", vehicleHandler(db))
+
+	return r
+}
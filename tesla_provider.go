@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/time/rate"
+)
+
+// ===================== Tesla Fleet API provider =====================
+
+// teslaVehicleData is the subset of the Tesla Fleet API "vehicle_data"
+// response that we map onto DecodedVehicle. The real payload has many more
+// fields; we only keep what we normalize.
+type teslaVehicleData struct {
+	Response struct {
+		VIN           string `json:"vin"`
+		DisplayName   string `json:"display_name"`
+		VehicleConfig struct {
+			CarType string `json:"car_type"`
+			Trim    string `json:"trim_badging"`
+		} `json:"vehicle_config"`
+	} `json:"response"`
+}
+
+// TeslaProvider decodes VINs via the Tesla Fleet API. Unlike NHTSA, Tesla's
+// API only returns data for vehicles registered to the authenticated
+// account, so this provider is mostly useful for fleet/owner integrations
+// rather than arbitrary third-party VINs.
+type TeslaProvider struct {
+	baseURL string
+	client  *http.Client
+	limiter *rate.Limiter
+}
+
+// NewTeslaProvider builds a TeslaProvider authenticated via OAuth2 client
+// credentials (TESLA_CLIENT_ID / TESLA_CLIENT_SECRET / TESLA_TOKEN_URL env
+// vars). It returns an error instead of panicking so callers can choose to
+// skip registering the provider when Tesla credentials aren't configured.
+func NewTeslaProvider() (*TeslaProvider, error) {
+	clientID := os.Getenv("TESLA_CLIENT_ID")
+	clientSecret := os.Getenv("TESLA_CLIENT_SECRET")
+	if clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("TESLA_CLIENT_ID/TESLA_CLIENT_SECRET not set")
+	}
+
+	tokenURL := os.Getenv("TESLA_TOKEN_URL")
+	if tokenURL == "" {
+		tokenURL = "https://auth.tesla.com/oauth2/v3/token"
+	}
+	baseURL := os.Getenv("TESLA_FLEET_API_URL")
+	if baseURL == "" {
+		baseURL = "https://fleet-api.prd.na.vn.cloud.tesla.com"
+	}
+
+	cfg := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       []string{"vehicle_device_data"},
+	}
+
+	return newTeslaProvider(baseURL, cfg.Client(context.Background()), rate.NewLimiter(rate.Limit(2), 2)), nil
+}
+
+// newTeslaProvider builds a TeslaProvider from an already-authenticated
+// client, letting tests inject an httptest server and a plain client in
+// place of the real OAuth2 flow.
+func newTeslaProvider(baseURL string, client *http.Client, limiter *rate.Limiter) *TeslaProvider {
+	return &TeslaProvider{baseURL: baseURL, client: client, limiter: limiter}
+}
+
+func (p *TeslaProvider) Name() string { return "tesla" }
+
+func (p *TeslaProvider) Decode(ctx context.Context, vin string) (*DecodedVehicle, error) {
+	if vin == "" {
+		return nil, fmt.Errorf("vin vazio")
+	}
+	if err := p.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("tesla rate limiter: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/1/vehicles/%s/vehicle_data", p.baseURL, vin)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tesla fleet api status %d", resp.StatusCode)
+	}
+
+	var tv teslaVehicleData
+	if err := json.NewDecoder(resp.Body).Decode(&tv); err != nil {
+		return nil, fmt.Errorf("unmarshal tesla json: %w", err)
+	}
+
+	raw := map[string]interface{}{
+		"display_name": tv.Response.DisplayName,
+		"car_type":     tv.Response.VehicleConfig.CarType,
+		"trim":         tv.Response.VehicleConfig.Trim,
+	}
+
+	return &DecodedVehicle{
+		VIN:          vin,
+		Make:         "Tesla",
+		Model:        tv.Response.VehicleConfig.CarType,
+		Manufacturer: "Tesla, Inc.",
+		FuelType:     "Electric",
+		Source:       p.Name(),
+		Raw:          raw,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func newTestTeslaProvider(handler http.HandlerFunc) (*TeslaProvider, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+	p := newTeslaProvider(srv.URL, &http.Client{Timeout: 2 * time.Second}, rate.NewLimiter(rate.Inf, 1))
+	return p, srv
+}
+
+func TestTeslaProviderDecodeSuccess(t *testing.T) {
+	p, srv := newTestTeslaProvider(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"response": {
+				"vin": "5YJ3E1EA1JF000001",
+				"display_name": "My Model 3",
+				"vehicle_config": {"car_type": "model3", "trim_badging": "p74d"}
+			}
+		}`))
+	})
+	defer srv.Close()
+
+	dv, err := p.Decode(context.Background(), "5YJ3E1EA1JF000001")
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if dv.Make != "Tesla" || dv.Model != "model3" || dv.Source != "tesla" {
+		t.Fatalf("unexpected decoded vehicle: %+v", dv)
+	}
+}
+
+func TestTeslaProviderDecodeNon200(t *testing.T) {
+	p, srv := newTestTeslaProvider(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+	defer srv.Close()
+
+	if _, err := p.Decode(context.Background(), "5YJ3E1EA1JF000001"); err == nil {
+		t.Fatal("expected error for non-200 upstream response")
+	}
+}
+
+func TestTeslaProviderDecodeEmptyResponse(t *testing.T) {
+	p, srv := newTestTeslaProvider(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"response": {}}`))
+	})
+	defer srv.Close()
+
+	dv, err := p.Decode(context.Background(), "5YJ3E1EA1JF000001")
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if dv.Model != "" {
+		t.Fatalf("expected empty model for empty response, got %+v", dv)
+	}
+}
+
+func TestTeslaProviderDecodeRateLimiterCtxCancel(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"response": {}}`))
+	}))
+	defer srv.Close()
+
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	p := newTeslaProvider(srv.URL, &http.Client{Timeout: 2 * time.Second}, limiter)
+
+	if _, err := p.Decode(context.Background(), "5YJ3E1EA1JF000001"); err != nil {
+		t.Fatalf("first Decode should consume the burst token without error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := p.Decode(ctx, "5YJ3E1EA1JF000001"); err == nil {
+		t.Fatal("expected error from canceled context while waiting on the rate limiter")
+	}
+	if calls != 1 {
+		t.Fatalf("expected upstream to be called exactly once, got %d", calls)
+	}
+}
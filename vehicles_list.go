@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ===================== GET /vehicles listing =====================
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+
+	// largeOffsetThreshold is the OFFSET past which listVehiclesOffset
+	// switches to a keyset seek instead of asking Postgres to skip that
+	// many rows on every request.
+	largeOffsetThreshold = 1000
+)
+
+type vehicleListResponse struct {
+	Items    []map[string]interface{} `json:"items"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
+	Total    int                      `json:"total"`
+}
+
+// listVehiclesHandler supports ?make=, ?model_year=, ?fuel_type= filters
+// plus ?pn=/?ps= offset pagination, or explicit keyset pagination via
+// ?after_vin=. Once the requested offset passes largeOffsetThreshold,
+// listVehiclesOffset transparently seeks to the page boundary by vin
+// instead of asking Postgres to walk and discard that many rows, so deep
+// pages don't turn into a growing OFFSET scan.
+func listVehiclesHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		filters := map[string]string{}
+		for _, key := range []string{"make", "model_year", "fuel_type"} {
+			if v := q.Get(key); v != "" {
+				filters[key] = v
+			}
+		}
+
+		if afterVIN := q.Get("after_vin"); afterVIN != "" {
+			items, err := listVehiclesKeyset(r.Context(), db, filters, afterVIN, parsePageSize(q))
+			if err != nil {
+				jsonWrite(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			jsonWrite(w, http.StatusOK, map[string]any{"items": items, "page_size": parsePageSize(q)})
+			return
+		}
+
+		page := parsePage(q)
+		pageSize := parsePageSize(q)
+
+		items, total, err := listVehiclesOffset(r.Context(), db, filters, page, pageSize)
+		if err != nil {
+			jsonWrite(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		jsonWrite(w, http.StatusOK, vehicleListResponse{
+			Items:    items,
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		})
+	}
+}
+
+func parsePage(q url.Values) int {
+	if n, err := strconv.Atoi(q.Get("pn")); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+func parsePageSize(q url.Values) int {
+	if n, err := strconv.Atoi(q.Get("ps")); err == nil && n > 0 {
+		if n > maxPageSize {
+			return maxPageSize
+		}
+		return n
+	}
+	return defaultPageSize
+}
+
+func vehicleListColumns() string {
+	return `vin, make, model, model_year, manufacturer,
+            plant_country, plant_state, body_class, engine_cylinders, fuel_type, source, raw`
+}
+
+func scanVehicleRow(rows *sql.Rows) (map[string]interface{}, error) {
+	var (
+		vin, make, model, modelYear, manufacturer, plantCountry, plantState, bodyClass, engineCylinders, fuelType, source string
+		raw                                                                                                               []byte
+	)
+	if err := rows.Scan(&vin, &make, &model, &modelYear, &manufacturer, &plantCountry, &plantState, &bodyClass, &engineCylinders, &fuelType, &source, &raw); err != nil {
+		return nil, err
+	}
+
+	var rawJSON map[string]interface{}
+	_ = json.Unmarshal(raw, &rawJSON)
+
+	return map[string]interface{}{
+		"vin":              vin,
+		"make":             make,
+		"model":            model,
+		"model_year":       modelYear,
+		"manufacturer":     manufacturer,
+		"plant_country":    plantCountry,
+		"plant_state":      plantState,
+		"body_class":       bodyClass,
+		"engine_cylinders": engineCylinders,
+		"fuel_type":        fuelType,
+		"source":           source,
+		"raw":              rawJSON,
+	}, nil
+}
+
+// filterClause builds a "WHERE make=$1 AND fuel_type=$2" style clause (in a
+// stable column order) plus the matching arg list.
+func filterClause(filters map[string]string) (string, []interface{}) {
+	if len(filters) == 0 {
+		return "", nil
+	}
+
+	clause := " WHERE "
+	args := make([]interface{}, 0, len(filters))
+	first := true
+	for _, col := range []string{"make", "model_year", "fuel_type"} {
+		v, ok := filters[col]
+		if !ok {
+			continue
+		}
+		if !first {
+			clause += " AND "
+		}
+		first = false
+		args = append(args, v)
+		clause += col + "=$" + strconv.Itoa(len(args))
+	}
+	return clause, args
+}
+
+func listVehiclesOffset(ctx context.Context, db *sql.DB, filters map[string]string, page, pageSize int) ([]map[string]interface{}, int, error) {
+	where, args := filterClause(filters)
+
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM vehicles"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if offset > largeOffsetThreshold {
+		anchor, found, err := seekAnchorVIN(ctx, db, where, args, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !found {
+			return []map[string]interface{}{}, total, nil
+		}
+		items, err := queryVehiclesAfter(ctx, db, where, args, anchor, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		return items, total, nil
+	}
+
+	limitArgs := append(append([]interface{}{}, args...), pageSize, offset)
+	rows, err := db.QueryContext(ctx, `
+        SELECT `+vehicleListColumns()+`
+        FROM vehicles`+where+`
+        ORDER BY vin
+        LIMIT $`+strconv.Itoa(len(limitArgs)-1)+` OFFSET $`+strconv.Itoa(len(limitArgs)),
+		limitArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []map[string]interface{}{}
+	for rows.Next() {
+		item, err := scanVehicleRow(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// seekAnchorVIN finds the vin immediately preceding a large offset, so the
+// actual page can be fetched with a cheap "vin > anchor" seek instead of an
+// OFFSET scan. The second return value is false if the offset is beyond the
+// end of the filtered result set.
+func seekAnchorVIN(ctx context.Context, db *sql.DB, where string, args []interface{}, offset int) (string, bool, error) {
+	anchorArgs := append(append([]interface{}{}, args...), offset-1)
+	var anchor string
+	err := db.QueryRowContext(ctx, `
+        SELECT vin
+        FROM vehicles`+where+`
+        ORDER BY vin
+        OFFSET $`+strconv.Itoa(len(anchorArgs))+`
+        LIMIT 1`,
+		anchorArgs...).Scan(&anchor)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return anchor, true, nil
+}
+
+// queryVehiclesAfter fetches up to pageSize rows with vin > afterVIN,
+// honoring the same filters as the offset query it's seeking within.
+func queryVehiclesAfter(ctx context.Context, db *sql.DB, where string, args []interface{}, afterVIN string, pageSize int) ([]map[string]interface{}, error) {
+	if where == "" {
+		where = " WHERE vin > $1"
+	} else {
+		where += " AND vin > $" + strconv.Itoa(len(args)+1)
+	}
+	args = append(append([]interface{}{}, args...), afterVIN, pageSize)
+
+	rows, err := db.QueryContext(ctx, `
+        SELECT `+vehicleListColumns()+`
+        FROM vehicles`+where+`
+        ORDER BY vin
+        LIMIT $`+strconv.Itoa(len(args)),
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []map[string]interface{}{}
+	for rows.Next() {
+		item, err := scanVehicleRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// listVehiclesKeyset paginates by vin > after_vin instead of OFFSET, which
+// stays fast regardless of how deep into the table the caller has paged.
+func listVehiclesKeyset(ctx context.Context, db *sql.DB, filters map[string]string, afterVIN string, pageSize int) ([]map[string]interface{}, error) {
+	where, args := filterClause(filters)
+	return queryVehiclesAfter(ctx, db, where, args, afterVIN, pageSize)
+}